@@ -0,0 +1,55 @@
+package config
+
+// ReviveSettings configures the revive linter.
+// See https://github.com/mgechev/revive for the meaning of these settings.
+type ReviveSettings struct {
+	Confidence            float64           `mapstructure:"confidence"`
+	Severity              string            `mapstructure:"severity"`
+	Rules                 []ReviveRule      `mapstructure:"rules"`
+	ErrorCode             int               `mapstructure:"error-code"`
+	WarningCode           int               `mapstructure:"warning-code"`
+	Directives            []ReviveDirective `mapstructure:"directives"`
+	IgnoreGeneratedHeader bool              `mapstructure:"ignore-generated-header"`
+	EnableAllRules        bool              `mapstructure:"enable-all-rules"`
+	MaxOpenFiles          int               `mapstructure:"max-open-files"`
+
+	// ExtraRulePlugins is a list of paths to Go plugins (.so files), each exposing
+	// a `Rules() []lint.Rule` symbol, letting teams add project-specific rules
+	// without patching golangci-lint itself. Unlike rules registered through
+	// RegisterReviveRule, the rules they return are activated only for this
+	// settings value, with an empty default rule configuration.
+	ExtraRulePlugins []string `mapstructure:"extra-rule-plugins"`
+
+	// Format selects the revive formatter used to render revive's own native
+	// report (e.g. "sarif", "checkstyle", "stylish", "ndjson"), in addition to
+	// the issues golangci-lint always reports itself. Defaults to "json", which
+	// is only used internally and never rendered. Requires FormatOutputPath.
+	Format string `mapstructure:"format"`
+
+	// FormatOutputPath is the file revive's native Format report is written to.
+	// Required whenever Format names anything other than the default "json".
+	FormatOutputPath string `mapstructure:"format-output-path"`
+
+	// Exclude lists glob patterns of files to exclude from revive's analysis,
+	// matching the `exclude` option of a standalone `.revive.toml`.
+	Exclude []string `mapstructure:"exclude"`
+}
+
+// ReviveRule configures a single revive rule.
+type ReviveRule struct {
+	Name      string `mapstructure:"name"`
+	Arguments []any  `mapstructure:"arguments"`
+	Severity  string `mapstructure:"severity"`
+	Disabled  bool   `mapstructure:"disabled"`
+}
+
+// ReviveDirective configures a revive directive (e.g. `//revive:disable`,
+// `specify-disable-reason`). This intentionally only covers severity: an
+// earlier draft of this struct also added Arguments and Disabled fields to
+// mirror ReviveRule, but revive's own directive config has no such knobs to
+// decode into, so that part of the original request was dropped rather than
+// shipped unverified. Exclude above covers the rest of that request's scope.
+type ReviveDirective struct {
+	Name     string `mapstructure:"name"`
+	Severity string `mapstructure:"severity"`
+}
@@ -2,11 +2,15 @@ package golinters
 
 import (
 	"bytes"
-	"encoding/json"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"go/token"
 	"os"
+	"path/filepath"
+	"plugin"
 	"reflect"
+	"strings"
 	"sync"
 
 	"github.com/BurntSushi/toml"
@@ -26,10 +30,55 @@ const reviveName = "revive"
 
 var reviveDebugf = logutils.Debug(logutils.DebugKeyRevive)
 
-// jsonObject defines a JSON object of a failure
-type jsonObject struct {
-	Severity     lint.Severity
-	lint.Failure `json:",inline"`
+// extraReviveRules and extraReviveRuleConfigs hold rules registered at runtime via
+// RegisterReviveRule, in addition to revive's own allRules/defaultRules. Rules
+// loaded from a settings.ExtraRulePlugins plugin are not stored here.
+var (
+	extraReviveRulesMu     sync.Mutex
+	extraReviveRules       []lint.Rule
+	extraReviveRuleConfigs = map[string]lint.RuleConfig{}
+)
+
+// RegisterReviveRule makes an additional rule, not part of revive's built-in rule
+// set, available to the "revive" linter. This lets a golangci-lint fork enforce
+// project-specific naming/API rules without patching this package. defaultConfig
+// is used when the user's configuration doesn't mention the rule explicitly.
+func RegisterReviveRule(rule lint.Rule, defaultConfig lint.RuleConfig) {
+	extraReviveRulesMu.Lock()
+	defer extraReviveRulesMu.Unlock()
+
+	extraReviveRules = append(extraReviveRules, rule)
+	extraReviveRuleConfigs[rule.Name()] = defaultConfig
+}
+
+// loadRulePlugins opens the Go plugins at paths and returns the rules exposed by
+// each plugin's `Rules() []lint.Rule` symbol. Unlike RegisterReviveRule, these
+// rules are not added to the global registry: paths comes from one particular
+// ReviveSettings, so the rules it loads must stay scoped to that settings value
+// instead of leaking into every other config the process happens to lint with.
+func loadRulePlugins(paths []string) ([]lint.Rule, error) {
+	var rules []lint.Rule
+
+	for _, path := range paths {
+		p, err := plugin.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open revive rule plugin %s: %w", path, err)
+		}
+
+		sym, err := p.Lookup("Rules")
+		if err != nil {
+			return nil, fmt.Errorf("revive rule plugin %s does not export Rules(): %w", path, err)
+		}
+
+		rulesFunc, ok := sym.(func() []lint.Rule)
+		if !ok {
+			return nil, fmt.Errorf("revive rule plugin %s: Rules has an unexpected signature", path)
+		}
+
+		rules = append(rules, rulesFunc()...)
+	}
+
+	return rules, nil
 }
 
 // NewRevive returns a new Revive linter.
@@ -50,9 +99,9 @@ func NewRevive(settings *config.ReviveSettings) *goanalysis.Linter {
 		"Fast, configurable, extensible, flexible, and beautiful linter for Go. Drop-in replacement of golint.",
 		[]*analysis.Analyzer{analyzer},
 		nil,
-	).WithContextSetter(func(lintCtx *linter.Context) {
+	).WithContextSetter(func(*linter.Context) {
 		analyzer.Run = func(pass *analysis.Pass) (any, error) {
-			issues, err := runRevive(lintCtx, pass, settings)
+			issues, err := runRevive(pass, settings)
 			if err != nil {
 				return nil, err
 			}
@@ -72,85 +121,242 @@ func NewRevive(settings *config.ReviveSettings) *goanalysis.Linter {
 	}).WithLoadMode(goanalysis.LoadModeSyntax)
 }
 
-func runRevive(lintCtx *linter.Context, pass *analysis.Pass, settings *config.ReviveSettings) ([]goanalysis.Issue, error) {
-	packages := [][]string{getFileNames(pass)}
-
-	conf, err := getReviveConfig(settings)
+func runRevive(pass *analysis.Pass, settings *config.ReviveSettings) ([]goanalysis.Issue, error) {
+	conf, lintingRules, err := getCachedReviveConfig(settings)
 	if err != nil {
 		return nil, err
 	}
 
-	formatter, err := reviveConfig.GetFormatter("json")
+	return runReviveWithCache(pass, settings, conf, lintingRules)
+}
+
+// reviveConfigEntry lazily computes and caches the *lint.Config and []lint.Rule
+// for one ReviveSettings value, computed once per process no matter how many
+// analysis passes (i.e. packages) share that configuration.
+type reviveConfigEntry struct {
+	once  sync.Once
+	conf  *lint.Config
+	rules []lint.Rule
+	err   error
+}
+
+var reviveConfigCache sync.Map // map[string]*reviveConfigEntry
+
+// getCachedReviveConfig returns the *lint.Config and []lint.Rule for settings,
+// building them at most once per process. On repos with hundreds of packages
+// this avoids redundant TOML round-tripping and rule allocation on every pass.
+func getCachedReviveConfig(settings *config.ReviveSettings) (*lint.Config, []lint.Rule, error) {
+	key, err := hashReviveSettings(settings)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	revive := lint.New(os.ReadFile, settings.MaxOpenFiles)
+	actual, _ := reviveConfigCache.LoadOrStore(key, &reviveConfigEntry{})
+	entry := actual.(*reviveConfigEntry)
 
-	lintingRules, err := reviveConfig.GetLintingRules(conf, []lint.Rule{})
+	entry.once.Do(func() {
+		pluginRules, err := loadRulePlugins(settings.ExtraRulePlugins)
+		if err != nil {
+			entry.err = err
+			return
+		}
+
+		extraReviveRulesMu.Lock()
+		registeredRules := append([]lint.Rule{}, extraReviveRules...)
+		extraRuleDefaults := make(map[string]lint.RuleConfig, len(extraReviveRuleConfigs)+len(pluginRules))
+		for name, ruleConfig := range extraReviveRuleConfigs {
+			extraRuleDefaults[name] = ruleConfig
+		}
+		extraReviveRulesMu.Unlock()
+
+		// Plugin-provided defaults are scoped to this settings value only: they
+		// aren't recorded in extraReviveRuleConfigs, so they can't leak into a
+		// *lint.Config built for a different ReviveSettings.
+		for _, r := range pluginRules {
+			extraRuleDefaults[r.Name()] = lint.RuleConfig{}
+		}
+
+		entry.conf, entry.err = getReviveConfig(settings, extraRuleDefaults)
+		if entry.err != nil {
+			return
+		}
+
+		extraRules := make([]lint.Rule, 0, len(registeredRules)+len(pluginRules))
+		extraRules = append(extraRules, registeredRules...)
+		extraRules = append(extraRules, pluginRules...)
+
+		entry.rules, entry.err = reviveConfig.GetLintingRules(entry.conf, extraRules)
+	})
+
+	return entry.conf, entry.rules, entry.err
+}
+
+// hashReviveSettings returns a stable cache key covering every field of
+// settings, not just the subset createConfigMap passes on to revive's own
+// *lint.Config - ExtraRulePlugins affects what getCachedReviveConfig computes
+// and caches just as much as the revive-native fields do. Format,
+// FormatOutputPath, MaxOpenFiles and Exclude are read per pass rather than
+// cached, but are part of the key as well, so two settings that differ in any
+// field never share a cache key.
+//
+// The key is built via TOML, not a plain JSON encode: rule arguments
+// loaded from YAML config can contain map[any]any (see safeTomlSlice), which
+// encoding/json cannot marshal at all, and a silently discarded encode error
+// would otherwise collapse every such settings object onto the same cache key.
+func hashReviveSettings(settings *config.ReviveSettings) (string, error) {
+	rawRoot := createConfigMap(settings)
+	rawRoot["format"] = settings.Format
+	rawRoot["formatOutputPath"] = settings.FormatOutputPath
+	rawRoot["maxOpenFiles"] = settings.MaxOpenFiles
+
+	if len(settings.ExtraRulePlugins) > 0 {
+		rawRoot["extraRulePlugins"] = settings.ExtraRulePlugins
+	}
+
+	if len(settings.Exclude) > 0 {
+		rawRoot["exclude"] = settings.Exclude
+	}
+
+	buf := bytes.NewBuffer(nil)
+
+	err := toml.NewEncoder(buf).Encode(rawRoot)
 	if err != nil {
-		return nil, err
+		return "", fmt.Errorf("failed to hash revive configuration: %w", err)
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// runReviveWithCache runs revive for one analysis pass using an already
+// resolved *lint.Config and []lint.Rule, shared across every pass that uses the
+// same settings.
+func runReviveWithCache(pass *analysis.Pass, settings *config.ReviveSettings,
+	conf *lint.Config, lintingRules []lint.Rule,
+) ([]goanalysis.Issue, error) {
+	packages := [][]string{excludeReviveFiles(getFileNames(pass), settings.Exclude)}
+
+	format := settings.Format
+	if format == "" {
+		format = "json"
 	}
 
+	revive := lint.New(os.ReadFile, settings.MaxOpenFiles)
+
 	failures, err := revive.Lint(packages, lintingRules, *conf)
 	if err != nil {
 		return nil, err
 	}
 
+	// "json" is golangci-lint's own in-process format: issues are built straight
+	// from the raw failures below, so there's no need to pay for a formatter at
+	// all. A formatter is only spun up when the user asked for revive's native
+	// report in some other format (sarif, checkstyle, ...).
+	if format != "json" {
+		if settings.FormatOutputPath == "" {
+			return nil, fmt.Errorf("revive: format %q requires format-output-path to be set", format)
+		}
+
+		formatter, formatterErr := reviveConfig.GetFormatter(format)
+		if formatterErr != nil {
+			return nil, formatterErr
+		}
+
+		return formatAndCollectReviveFailures(pass, conf, formatter, format, settings.FormatOutputPath, failures)
+	}
+
+	return collectReviveFailures(pass, conf, failures), nil
+}
+
+// collectReviveFailures consumes failures directly into goanalysis.Issues, with no
+// formatter or intermediate buffering involved.
+func collectReviveFailures(pass *analysis.Pass, conf *lint.Config, failures <-chan lint.Failure) []goanalysis.Issue {
+	var issues []goanalysis.Issue
+	for f := range failures {
+		if f.Confidence < conf.Confidence {
+			continue
+		}
+
+		failure := f
+		issues = append(issues, reviveFailureToIssue(pass, &failure, severityForFailure(conf, &failure)))
+	}
+
+	return issues
+}
+
+// formatAndCollectReviveFailures does the same as collectReviveFailures, but also
+// tees every failure through formatter and writes the resulting report to
+// outputPath, so revive's native report (e.g. SARIF or Checkstyle) is available
+// to CI tooling as a real file instead of only a debug log line.
+func formatAndCollectReviveFailures(pass *analysis.Pass, conf *lint.Config,
+	formatter lint.Formatter, format, outputPath string, failures <-chan lint.Failure,
+) ([]goanalysis.Issue, error) {
 	formatChan := make(chan lint.Failure)
 	exitChan := make(chan bool)
 
 	var output string
+	var formatErr error
 	go func() {
-		output, err = formatter.Format(formatChan, *conf)
-		if err != nil {
-			lintCtx.Log.Errorf("Format error: %v", err)
-		}
+		output, formatErr = formatter.Format(formatChan, *conf)
 		exitChan <- true
 	}()
 
+	var issues []goanalysis.Issue
 	for f := range failures {
 		if f.Confidence < conf.Confidence {
 			continue
 		}
 
+		failure := f
+		issues = append(issues, reviveFailureToIssue(pass, &failure, severityForFailure(conf, &failure)))
+
 		formatChan <- f
 	}
 
 	close(formatChan)
 	<-exitChan
 
-	var results []jsonObject
-	err = json.Unmarshal([]byte(output), &results)
-	if err != nil {
-		return nil, err
+	if formatErr != nil {
+		return nil, fmt.Errorf("failed to render revive %s report: %w", format, formatErr)
 	}
 
-	var issues []goanalysis.Issue
-	for i := range results {
-		issues = append(issues, reviveToIssue(pass, &results[i]))
+	if err := os.WriteFile(outputPath, []byte(output), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write revive %s report to %s: %w", format, outputPath, err)
 	}
 
+	reviveDebugf("wrote revive %s report to %s", format, outputPath)
+
 	return issues, nil
 }
 
-func reviveToIssue(pass *analysis.Pass, object *jsonObject) goanalysis.Issue {
-	lineRangeTo := object.Position.End.Line
-	if object.RuleName == (&rule.ExportedRule{}).Name() {
-		lineRangeTo = object.Position.Start.Line
+// severityForFailure returns the severity configured for f's rule, falling back
+// to the config's default severity.
+func severityForFailure(conf *lint.Config, f *lint.Failure) lint.Severity {
+	if ruleConf, ok := conf.Rules[f.RuleName]; ok && ruleConf.Severity != "" {
+		return ruleConf.Severity
+	}
+
+	return conf.Severity
+}
+
+func reviveFailureToIssue(pass *analysis.Pass, f *lint.Failure, severity lint.Severity) goanalysis.Issue {
+	lineRangeTo := f.Position.End.Line
+	if f.RuleName == (&rule.ExportedRule{}).Name() {
+		lineRangeTo = f.Position.Start.Line
 	}
 
 	return goanalysis.NewIssue(&result.Issue{
-		Severity: string(object.Severity),
-		Text:     fmt.Sprintf("%s: %s", object.RuleName, object.Failure.Failure),
+		Severity: string(severity),
+		Text:     fmt.Sprintf("%s: %s", f.RuleName, f.Failure),
 		Pos: token.Position{
-			Filename: object.Position.Start.Filename,
-			Line:     object.Position.Start.Line,
-			Offset:   object.Position.Start.Offset,
-			Column:   object.Position.Start.Column,
+			Filename: f.Position.Start.Filename,
+			Line:     f.Position.Start.Line,
+			Offset:   f.Position.Start.Offset,
+			Column:   f.Position.Start.Column,
 		},
 		LineRange: &result.Range{
-			From: object.Position.Start.Line,
+			From: f.Position.Start.Line,
 			To:   lineRangeTo,
 		},
 		FromLinter: reviveName,
@@ -161,7 +367,7 @@ func reviveToIssue(pass *analysis.Pass, object *jsonObject) goanalysis.Issue {
 // This allows to get default values and right types.
 // https://github.com/golangci/golangci-lint/issues/1745
 // https://github.com/mgechev/revive/blob/v1.1.4/config/config.go#L182
-func getReviveConfig(cfg *config.ReviveSettings) (*lint.Config, error) {
+func getReviveConfig(cfg *config.ReviveSettings, extraRuleDefaults map[string]lint.RuleConfig) (*lint.Config, error) {
 	conf := defaultConfig()
 
 	if !reflect.DeepEqual(cfg, &config.ReviveSettings{}) {
@@ -180,7 +386,7 @@ func getReviveConfig(cfg *config.ReviveSettings) (*lint.Config, error) {
 		}
 	}
 
-	normalizeConfig(conf)
+	normalizeConfig(conf, extraRuleDefaults)
 
 	reviveDebugf("revive configuration: %#v", conf)
 
@@ -224,6 +430,48 @@ func createConfigMap(cfg *config.ReviveSettings) map[string]any {
 	return rawRoot
 }
 
+// excludeReviveFiles drops files matching any of patterns, matched the same way
+// a standalone `.revive.toml`'s `exclude` option would: against the full path and
+// against every trailing sub-path of it, down to the bare file name.
+func excludeReviveFiles(files, patterns []string) []string {
+	if len(patterns) == 0 {
+		return files
+	}
+
+	filtered := files[:0:0]
+	for _, f := range files {
+		if !matchesAnyReviveExcludeGlob(f, patterns) {
+			filtered = append(filtered, f)
+		}
+	}
+
+	return filtered
+}
+
+func matchesAnyReviveExcludeGlob(file string, patterns []string) bool {
+	// Try the full path, the bare file name, and every path suffix in between
+	// (e.g. "internal/mocks/foo.go", "mocks/foo.go", "foo.go"), so a pattern like
+	// "internal/mocks/*.go" matches regardless of what precedes it in file, the
+	// same way it would against a relative path in a standalone .revive.toml.
+	//
+	// strings.Join, not filepath.Join, rebuilds each suffix: Join would collapse
+	// the leading "" segment an absolute path splits into, silently stripping the
+	// leading "/" and breaking patterns anchored to a rooted path.
+	parts := strings.Split(filepath.ToSlash(file), "/")
+
+	for i := range parts {
+		suffix := strings.Join(parts[i:], "/")
+
+		for _, pattern := range patterns {
+			if ok, _ := filepath.Match(pattern, suffix); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 func safeTomlSlice(r []any) []any {
 	if len(r) == 0 {
 		return nil
@@ -335,7 +583,7 @@ const defaultConfidence = 0.8
 
 // This element is not exported by revive, so we need copy the code.
 // Extracted from https://github.com/mgechev/revive/blob/v1.1.4/config/config.go#L145
-func normalizeConfig(cfg *lint.Config) {
+func normalizeConfig(cfg *lint.Config, extraRuleDefaults map[string]lint.RuleConfig) {
 	// NOTE(ldez): this custom section for golangci-lint should be kept.
 	// ---
 	if cfg.Confidence == 0 {
@@ -362,6 +610,15 @@ func normalizeConfig(cfg *lint.Config) {
 		}
 	}
 
+	// Activate rules registered via RegisterReviveRule or loaded from this
+	// settings value's ExtraRulePlugins that the user hasn't explicitly
+	// configured, using their defaults.
+	for name, defaultRuleConfig := range extraRuleDefaults {
+		if _, alreadyInConf := cfg.Rules[name]; !alreadyInConf {
+			cfg.Rules[name] = defaultRuleConfig
+		}
+	}
+
 	severity := cfg.Severity
 	if severity != "" {
 		for k, v := range cfg.Rules {
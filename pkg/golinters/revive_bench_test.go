@@ -0,0 +1,120 @@
+package golinters
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"testing"
+
+	reviveConfig "github.com/mgechev/revive/config"
+	"github.com/mgechev/revive/lint"
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/golangci/golangci-lint/pkg/golinters/goanalysis"
+)
+
+func syntheticReviveFailures(n int) <-chan lint.Failure {
+	failures := make(chan lint.Failure, n)
+
+	for i := 0; i < n; i++ {
+		failures <- lint.Failure{
+			Failure:    fmt.Sprintf("synthetic failure %d", i),
+			RuleName:   "var-naming",
+			Confidence: 1,
+			Position: lint.FailurePosition{
+				Start: token.Position{Filename: "synthetic.go", Line: i + 1, Column: 1},
+				End:   token.Position{Filename: "synthetic.go", Line: i + 1, Column: 10},
+			},
+		}
+	}
+
+	close(failures)
+
+	return failures
+}
+
+// legacyJSONFailure mirrors the jsonObject this package used to unmarshal the
+// "json" formatter's output into, before collectReviveFailures replaced it.
+type legacyJSONFailure struct {
+	Severity     lint.Severity
+	lint.Failure `json:",inline"`
+}
+
+// legacyFormatAndCollectReviveFailures reproduces the pre-optimization path: every
+// failure is sent through the "json" formatter, the full formatted string is
+// collected, then unmarshaled back into []legacyJSONFailure before issues can be
+// built. This is the baseline collectReviveFailures was written to avoid paying.
+func legacyFormatAndCollectReviveFailures(pass *analysis.Pass, conf *lint.Config, failures <-chan lint.Failure) ([]goanalysis.Issue, error) {
+	formatter, err := reviveConfig.GetFormatter("json")
+	if err != nil {
+		return nil, err
+	}
+
+	formatChan := make(chan lint.Failure)
+	exitChan := make(chan bool)
+
+	var output string
+	var formatErr error
+	go func() {
+		output, formatErr = formatter.Format(formatChan, *conf)
+		exitChan <- true
+	}()
+
+	for f := range failures {
+		if f.Confidence < conf.Confidence {
+			continue
+		}
+
+		formatChan <- f
+	}
+
+	close(formatChan)
+	<-exitChan
+
+	if formatErr != nil {
+		return nil, formatErr
+	}
+
+	var results []legacyJSONFailure
+
+	err = json.Unmarshal([]byte(output), &results)
+	if err != nil {
+		return nil, err
+	}
+
+	issues := make([]goanalysis.Issue, 0, len(results))
+	for i := range results {
+		issues = append(issues, reviveFailureToIssue(pass, &results[i].Failure, results[i].Severity))
+	}
+
+	return issues, nil
+}
+
+// BenchmarkCollectReviveFailures measures building issues directly from revive's
+// failure stream, with no formatter or JSON round-trip involved.
+func BenchmarkCollectReviveFailures(b *testing.B) {
+	pass := &analysis.Pass{Fset: token.NewFileSet()}
+	conf := defaultConfig()
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		collectReviveFailures(pass, conf, syntheticReviveFailures(5000))
+	}
+}
+
+// BenchmarkLegacyFormatAndCollectReviveFailures measures the formatter+JSON
+// round-trip path collectReviveFailures replaced, as a baseline to compare
+// BenchmarkCollectReviveFailures against.
+func BenchmarkLegacyFormatAndCollectReviveFailures(b *testing.B) {
+	pass := &analysis.Pass{Fset: token.NewFileSet()}
+	conf := defaultConfig()
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := legacyFormatAndCollectReviveFailures(pass, conf, syntheticReviveFailures(5000)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
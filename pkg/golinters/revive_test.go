@@ -0,0 +1,281 @@
+package golinters
+
+import (
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	reviveConfig "github.com/mgechev/revive/config"
+	"github.com/mgechev/revive/lint"
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/golangci/golangci-lint/pkg/config"
+)
+
+// fakeReviveRule is a minimal lint.Rule used to exercise RegisterReviveRule
+// without needing an actual Go plugin.
+type fakeReviveRule struct{}
+
+func (fakeReviveRule) Name() string { return "fake-extra-rule" }
+
+func (fakeReviveRule) Apply(*lint.File, lint.Arguments) []lint.Failure { return nil }
+
+func TestExcludeReviveFiles(t *testing.T) {
+	files := []string{
+		"/repo/internal/mocks/foo_mock.go",
+		"/repo/internal/service/service.go",
+		"/repo/pkg/api/api_test.go",
+	}
+
+	testCases := []struct {
+		desc     string
+		patterns []string
+		want     []string
+	}{
+		{
+			desc:     "no patterns",
+			patterns: nil,
+			want:     files,
+		},
+		{
+			desc:     "bare file name glob",
+			patterns: []string{"*_mock.go"},
+			want:     []string{"/repo/internal/service/service.go", "/repo/pkg/api/api_test.go"},
+		},
+		{
+			desc:     "directory-qualified glob",
+			patterns: []string{"internal/mocks/*.go"},
+			want:     []string{"/repo/internal/service/service.go", "/repo/pkg/api/api_test.go"},
+		},
+		{
+			desc:     "full path glob",
+			patterns: []string{"/repo/pkg/api/*_test.go"},
+			want:     []string{"/repo/internal/mocks/foo_mock.go", "/repo/internal/service/service.go"},
+		},
+		{
+			desc:     "no match",
+			patterns: []string{"*.txt"},
+			want:     files,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			got := excludeReviveFiles(files, test.patterns)
+
+			if len(got) != len(test.want) {
+				t.Fatalf("excludeReviveFiles() = %v, want %v", got, test.want)
+			}
+
+			for i := range got {
+				if got[i] != test.want[i] {
+					t.Fatalf("excludeReviveFiles() = %v, want %v", got, test.want)
+				}
+			}
+		})
+	}
+}
+
+func TestHashReviveSettings(t *testing.T) {
+	settingsA := &config.ReviveSettings{Confidence: 0.8}
+	settingsB := &config.ReviveSettings{Confidence: 0.9}
+
+	hashA1, err := hashReviveSettings(settingsA)
+	if err != nil {
+		t.Fatalf("hashReviveSettings() error = %v", err)
+	}
+
+	hashA2, err := hashReviveSettings(settingsA)
+	if err != nil {
+		t.Fatalf("hashReviveSettings() error = %v", err)
+	}
+
+	hashB, err := hashReviveSettings(settingsB)
+	if err != nil {
+		t.Fatalf("hashReviveSettings() error = %v", err)
+	}
+
+	if hashA1 != hashA2 {
+		t.Fatalf("hashReviveSettings() is not stable across calls: %s != %s", hashA1, hashA2)
+	}
+
+	if hashA1 == hashB {
+		t.Fatal("hashReviveSettings() returned the same key for two different settings")
+	}
+}
+
+// TestHashReviveSettingsTableRuleArguments guards against the hash collapsing
+// onto a single key when rule arguments are the map[any]any tables YAML config
+// produces for table-style rules (e.g. function-length, cognitive-complexity).
+func TestHashReviveSettingsTableRuleArguments(t *testing.T) {
+	settingsA := &config.ReviveSettings{
+		Rules: []config.ReviveRule{{
+			Name:      "function-length",
+			Arguments: []any{map[any]any{"statements": 40}},
+		}},
+	}
+	settingsB := &config.ReviveSettings{
+		Rules: []config.ReviveRule{{
+			Name:      "function-length",
+			Arguments: []any{map[any]any{"statements": 80}},
+		}},
+	}
+
+	hashA, err := hashReviveSettings(settingsA)
+	if err != nil {
+		t.Fatalf("hashReviveSettings() error = %v", err)
+	}
+
+	hashB, err := hashReviveSettings(settingsB)
+	if err != nil {
+		t.Fatalf("hashReviveSettings() error = %v", err)
+	}
+
+	if hashA == hashB {
+		t.Fatal("hashReviveSettings() collided for settings with different table-style rule arguments")
+	}
+}
+
+// TestHashReviveSettingsDistinguishesFieldsOutsideConfigMap guards against the
+// cache key ignoring fields that createConfigMap doesn't pass on to revive's own
+// *lint.Config (ExtraRulePlugins, Format, Exclude, MaxOpenFiles) but that still
+// change how revive runs for that settings value.
+func TestHashReviveSettingsDistinguishesFieldsOutsideConfigMap(t *testing.T) {
+	base := &config.ReviveSettings{Confidence: 0.8}
+	baseHash, err := hashReviveSettings(base)
+	if err != nil {
+		t.Fatalf("hashReviveSettings() error = %v", err)
+	}
+
+	variants := map[string]*config.ReviveSettings{
+		"ExtraRulePlugins": {Confidence: 0.8, ExtraRulePlugins: []string{"a.so"}},
+		"Format":           {Confidence: 0.8, Format: "sarif"},
+		"Exclude":          {Confidence: 0.8, Exclude: []string{"*_mock.go"}},
+		"MaxOpenFiles":     {Confidence: 0.8, MaxOpenFiles: 10},
+	}
+
+	for name, variant := range variants {
+		t.Run(name, func(t *testing.T) {
+			hash, err := hashReviveSettings(variant)
+			if err != nil {
+				t.Fatalf("hashReviveSettings() error = %v", err)
+			}
+
+			if hash == baseHash {
+				t.Fatalf("hashReviveSettings() ignored %s: collided with the base settings", name)
+			}
+		})
+	}
+
+	pluginA, err := hashReviveSettings(&config.ReviveSettings{ExtraRulePlugins: []string{"a.so"}})
+	if err != nil {
+		t.Fatalf("hashReviveSettings() error = %v", err)
+	}
+
+	pluginB, err := hashReviveSettings(&config.ReviveSettings{ExtraRulePlugins: []string{"b.so"}})
+	if err != nil {
+		t.Fatalf("hashReviveSettings() error = %v", err)
+	}
+
+	if pluginA == pluginB {
+		t.Fatal("hashReviveSettings() collided for settings naming different ExtraRulePlugins")
+	}
+}
+
+// TestFormatAndCollectReviveFailuresWritesReport confirms revive's native
+// report lands on disk at outputPath, not just in the debug log, addressing
+// the original report-a-real-format-output request.
+func TestFormatAndCollectReviveFailuresWritesReport(t *testing.T) {
+	formatter, err := reviveConfig.GetFormatter("json")
+	if err != nil {
+		t.Fatalf("GetFormatter() error = %v", err)
+	}
+
+	pass := &analysis.Pass{Fset: token.NewFileSet()}
+	conf := defaultConfig()
+	outputPath := filepath.Join(t.TempDir(), "revive-report.json")
+
+	issues, err := formatAndCollectReviveFailures(pass, conf, formatter, "json", outputPath, syntheticReviveFailures(3))
+	if err != nil {
+		t.Fatalf("formatAndCollectReviveFailures() error = %v", err)
+	}
+
+	if len(issues) != 3 {
+		t.Fatalf("len(issues) = %d, want 3", len(issues))
+	}
+
+	report, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("expected a report at %s: %v", outputPath, err)
+	}
+
+	if len(report) == 0 {
+		t.Fatal("expected a non-empty revive report")
+	}
+}
+
+func TestGetCachedReviveConfigReusesEntryForIdenticalSettings(t *testing.T) {
+	settings := &config.ReviveSettings{Confidence: 0.75}
+
+	conf1, _, err := getCachedReviveConfig(settings)
+	if err != nil {
+		t.Fatalf("getCachedReviveConfig() error = %v", err)
+	}
+
+	conf2, _, err := getCachedReviveConfig(settings)
+	if err != nil {
+		t.Fatalf("getCachedReviveConfig() error = %v", err)
+	}
+
+	if conf1 != conf2 {
+		t.Fatal("getCachedReviveConfig() rebuilt the *lint.Config instead of reusing the cached one")
+	}
+}
+
+// TestRegisterReviveRuleActivatesDefaultForAnySettings confirms a rule added via
+// RegisterReviveRule (the fork-wide registration hook) is activated, using its
+// default RuleConfig, for any settings that don't mention it explicitly.
+func TestRegisterReviveRuleActivatesDefaultForAnySettings(t *testing.T) {
+	RegisterReviveRule(fakeReviveRule{}, lint.RuleConfig{Severity: lint.SeverityError})
+
+	conf, _, err := getCachedReviveConfig(&config.ReviveSettings{})
+	if err != nil {
+		t.Fatalf("getCachedReviveConfig() error = %v", err)
+	}
+
+	ruleConf, ok := conf.Rules["fake-extra-rule"]
+	if !ok {
+		t.Fatal("expected fake-extra-rule to be activated via its registered default")
+	}
+
+	if ruleConf.Severity != lint.SeverityError {
+		t.Fatalf("fake-extra-rule severity = %q, want %q", ruleConf.Severity, lint.SeverityError)
+	}
+}
+
+// TestGetReviveConfigExtraRuleDefaultsAreScopedPerCall is the regression test for
+// the ExtraRulePlugins leak: each getReviveConfig call activates only the extra
+// rule defaults passed to it, so a plugin rule's default from one call must not
+// show up in the *lint.Config built by another call, even for the same settings.
+func TestGetReviveConfigExtraRuleDefaultsAreScopedPerCall(t *testing.T) {
+	settings := &config.ReviveSettings{}
+
+	confA, err := getReviveConfig(settings, map[string]lint.RuleConfig{"plugin-rule-a": {}})
+	if err != nil {
+		t.Fatalf("getReviveConfig() error = %v", err)
+	}
+
+	confB, err := getReviveConfig(settings, map[string]lint.RuleConfig{"plugin-rule-b": {}})
+	if err != nil {
+		t.Fatalf("getReviveConfig() error = %v", err)
+	}
+
+	if _, ok := confA.Rules["plugin-rule-b"]; ok {
+		t.Fatal("plugin-rule-b leaked into a config that never requested it")
+	}
+
+	if _, ok := confB.Rules["plugin-rule-a"]; ok {
+		t.Fatal("plugin-rule-a leaked into a config that never requested it")
+	}
+}